@@ -0,0 +1,29 @@
+package apps
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the group name for the apps API.
+const GroupName = "apps.openshift.io"
+
+// SchemeGroupVersion is the internal group version used to register these types.
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: runtime.APIVersionInternal}
+
+var (
+	// SchemeBuilder collects the functions that add the internal apps types to a scheme.
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme adds the internal apps types to a scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&DeploymentConfig{},
+		&DeploymentConfigRollback{},
+		&DeploymentConfigRollbackHistoryList{},
+		&DeploymentConfigRollbackPreview{},
+	)
+	return nil
+}
@@ -0,0 +1,45 @@
+package apps
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeploymentConfigRollbackHistoryList is an ordered list of the revisions of a DeploymentConfig
+// that are eligible to be used as the target of a rollback, most recent first.
+type DeploymentConfigRollbackHistoryList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+
+	// Items is the ordered list of revisions available for rollback.
+	Items []DeploymentConfigRollbackHistory
+}
+
+// DeploymentConfigRollbackHistory describes a single revision of a DeploymentConfig that can be
+// used as the target of a rollback, as reconstructed from the ReplicationController backing it.
+type DeploymentConfigRollbackHistory struct {
+	// Revision is the value of the openshift.io/deployment-config.latest-version annotation
+	// recorded on the backing ReplicationController.
+	Revision int64
+	// ReplicationControllerName is the name of the ReplicationController backing this revision.
+	ReplicationControllerName string
+	// CreationTimestamp is the time the backing ReplicationController was created.
+	CreationTimestamp metav1.Time
+	// ChangeCause is the value of the kubernetes.io/change-cause annotation on the backing
+	// ReplicationController or, if that is empty, on the DeploymentConfig encoded in it.
+	ChangeCause string
+	// Replicas is the replica count recorded in the backing ReplicationController's spec.
+	Replicas int32
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *DeploymentConfigRollbackHistoryList) DeepCopyObject() runtime.Object {
+	out := new(DeploymentConfigRollbackHistoryList)
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		out.Items = make([]DeploymentConfigRollbackHistory, len(in.Items))
+		copy(out.Items, in.Items)
+	}
+	return out
+}
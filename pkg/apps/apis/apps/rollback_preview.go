@@ -0,0 +1,89 @@
+package apps
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeploymentConfigRollbackPreview is returned instead of the generated DeploymentConfig when a
+// rollback Create is invoked with DryRun set to All. It lets a caller render a "kubectl diff"-style
+// summary of what a rollback would change before committing to it.
+type DeploymentConfigRollbackPreview struct {
+	metav1.TypeMeta
+
+	// GeneratedDeploymentConfig is the DeploymentConfig that would be produced by the rollback,
+	// exactly as a non-dry-run Create would return it.
+	GeneratedDeploymentConfig *DeploymentConfig
+
+	// Diff is a strategic-merge-patch, in JSON form, between the current DeploymentConfig and
+	// GeneratedDeploymentConfig.
+	Diff string
+
+	// ChangedImages lists the container image changes the rollback would apply, one entry per
+	// container whose image differs.
+	ChangedImages []ImageChange
+
+	// ChangedEnvironment lists the environment variable changes the rollback would apply, one
+	// entry per variable that is added, removed or changed in a container.
+	ChangedEnvironment []EnvVarChange
+
+	// ReplicaChange describes the replica count change the rollback would apply, or nil if the
+	// replica count would be unchanged.
+	ReplicaChange *ReplicaChange
+}
+
+// ImageChange describes a container image change between the current and the would-be rolled
+// back DeploymentConfig.
+type ImageChange struct {
+	// Container is the name of the container whose image changed.
+	Container string
+	// From is the image the container currently runs.
+	From string
+	// To is the image the container would run after the rollback.
+	To string
+}
+
+// EnvVarChange describes an environment variable change on a single container between the
+// current and the would-be rolled back DeploymentConfig.
+type EnvVarChange struct {
+	// Container is the name of the container whose environment changed.
+	Container string
+	// Name is the environment variable name.
+	Name string
+	// From is the variable's current value, or empty if it is being added.
+	From string
+	// To is the variable's value after the rollback, or empty if it is being removed.
+	To string
+}
+
+// ReplicaChange describes a replica count change between the current and the would-be rolled
+// back DeploymentConfig.
+type ReplicaChange struct {
+	// From is the current replica count.
+	From int32
+	// To is the replica count the rollback would apply.
+	To int32
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *DeploymentConfigRollbackPreview) DeepCopyObject() runtime.Object {
+	out := new(DeploymentConfigRollbackPreview)
+	out.TypeMeta = in.TypeMeta
+	if in.GeneratedDeploymentConfig != nil {
+		out.GeneratedDeploymentConfig = in.GeneratedDeploymentConfig.DeepCopy()
+	}
+	out.Diff = in.Diff
+	if in.ChangedImages != nil {
+		out.ChangedImages = make([]ImageChange, len(in.ChangedImages))
+		copy(out.ChangedImages, in.ChangedImages)
+	}
+	if in.ChangedEnvironment != nil {
+		out.ChangedEnvironment = make([]EnvVarChange, len(in.ChangedEnvironment))
+		copy(out.ChangedEnvironment, in.ChangedEnvironment)
+	}
+	if in.ReplicaChange != nil {
+		rc := *in.ReplicaChange
+		out.ReplicaChange = &rc
+	}
+	return out
+}
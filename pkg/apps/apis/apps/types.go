@@ -0,0 +1,62 @@
+package apps
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kapi "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// DeploymentConfigRollback provides the input to rollback generation.
+type DeploymentConfigRollback struct {
+	metav1.TypeMeta
+
+	// Name of the deployment config that will be rolled back.
+	Name string
+	// UpdatedAnnotations is a set of new annotations that will be added in the deployment config.
+	UpdatedAnnotations map[string]string
+	// Spec defines the options to rollback generation.
+	Spec DeploymentConfigRollbackSpec
+}
+
+// DeploymentConfigRollbackSpec represents the options for rollback generation.
+type DeploymentConfigRollbackSpec struct {
+	// From points to a ReplicationController which is a deployment.
+	From kapi.ObjectReference
+	// Revision to rollback to. If set to 0, rollback to the last revision.
+	Revision int64
+	// TargetReplicationController, if set, names the exact ReplicationController to roll back to,
+	// bypassing revision lookup entirely.
+	TargetReplicationController string
+	// ChangeCauseContains, if set, selects the newest historical ReplicationController whose
+	// kubernetes.io/change-cause annotation contains this substring.
+	ChangeCauseContains string
+	// TargetSelector, if set, selects the newest historical ReplicationController matching this
+	// label selector.
+	TargetSelector *metav1.LabelSelector
+	// IncludeTriggers specifies whether to include config Triggers.
+	IncludeTriggers bool
+	// IncludeTemplate specifies whether to include the PodTemplateSpec.
+	IncludeTemplate bool
+	// IncludeReplicationMeta specifies whether to include the replica count and selector.
+	IncludeReplicationMeta bool
+	// IncludeStrategy specifies whether to include the deployment Strategy.
+	IncludeStrategy bool
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *DeploymentConfigRollback) DeepCopyObject() runtime.Object {
+	out := new(DeploymentConfigRollback)
+	out.TypeMeta = in.TypeMeta
+	out.Name = in.Name
+	if in.UpdatedAnnotations != nil {
+		out.UpdatedAnnotations = make(map[string]string, len(in.UpdatedAnnotations))
+		for k, v := range in.UpdatedAnnotations {
+			out.UpdatedAnnotations[k] = v
+		}
+	}
+	out.Spec = in.Spec
+	if in.Spec.TargetSelector != nil {
+		out.Spec.TargetSelector = in.Spec.TargetSelector.DeepCopy()
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package validation
+
+import (
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	appsapi "github.com/openshift/origin/pkg/apps/apis/apps"
+)
+
+// ValidateDeploymentConfigRollback validates a DeploymentConfigRollback object.
+func ValidateDeploymentConfigRollback(rollback *appsapi.DeploymentConfigRollback) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(rollback.Name) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("name"), ""))
+	}
+
+	allErrs = append(allErrs, validateRollbackTarget(&rollback.Spec, field.NewPath("spec"))...)
+
+	return allErrs
+}
+
+// validateRollbackTarget ensures at most one target selector is set on the rollback spec: a
+// revision number, a ReplicationController name, a change-cause substring, or a label selector.
+// A spec with none of them set is valid and targets the revision before the latest one.
+func validateRollbackTarget(spec *appsapi.DeploymentConfigRollbackSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	set := 0
+	if spec.Revision > 0 {
+		set++
+	}
+	if len(spec.TargetReplicationController) > 0 {
+		set++
+	}
+	if len(spec.ChangeCauseContains) > 0 {
+		set++
+	}
+	if spec.TargetSelector != nil {
+		set++
+	}
+
+	switch {
+	case set > 1:
+		allErrs = append(allErrs, field.Invalid(fldPath, spec, "at most one of revision, targetReplicationController, changeCauseContains, or targetSelector may be set"))
+	case spec.TargetSelector != nil:
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(spec.TargetSelector, fldPath.Child("targetSelector"))...)
+	}
+
+	return allErrs
+}
@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsapi "github.com/openshift/origin/pkg/apps/apis/apps"
+)
+
+func TestValidateDeploymentConfigRollbackTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      appsapi.DeploymentConfigRollbackSpec
+		expectErr bool
+	}{
+		{
+			name:      "no selector set",
+			spec:      appsapi.DeploymentConfigRollbackSpec{},
+			expectErr: false,
+		},
+		{
+			name:      "revision only",
+			spec:      appsapi.DeploymentConfigRollbackSpec{Revision: 2},
+			expectErr: false,
+		},
+		{
+			name:      "target replication controller only",
+			spec:      appsapi.DeploymentConfigRollbackSpec{TargetReplicationController: "deploy-2"},
+			expectErr: false,
+		},
+		{
+			name:      "change cause only",
+			spec:      appsapi.DeploymentConfigRollbackSpec{ChangeCauseContains: "hotfix"},
+			expectErr: false,
+		},
+		{
+			name:      "label selector only",
+			spec:      appsapi.DeploymentConfigRollbackSpec{TargetSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"build": "v1.4.2"}}},
+			expectErr: false,
+		},
+		{
+			name: "revision and target replication controller both set",
+			spec: appsapi.DeploymentConfigRollbackSpec{
+				Revision:                    2,
+				TargetReplicationController: "deploy-2",
+			},
+			expectErr: true,
+		},
+		{
+			name: "change cause and label selector both set",
+			spec: appsapi.DeploymentConfigRollbackSpec{
+				ChangeCauseContains: "hotfix",
+				TargetSelector:      &metav1.LabelSelector{MatchLabels: map[string]string{"build": "v1.4.2"}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollback := &appsapi.DeploymentConfigRollback{Name: "config", Spec: tt.spec}
+			errs := ValidateDeploymentConfigRollback(rollback)
+			if tt.expectErr && len(errs) == 0 {
+				t.Errorf("expected a validation error, got none")
+			}
+			if !tt.expectErr && len(errs) != 0 {
+				t.Errorf("expected no validation error, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidateDeploymentConfigRollbackRequiresName(t *testing.T) {
+	errs := ValidateDeploymentConfigRollback(&appsapi.DeploymentConfigRollback{})
+	if len(errs) == 0 {
+		t.Errorf("expected a validation error for missing name, got none")
+	}
+}
@@ -0,0 +1,103 @@
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/api/apps"
+	appsclient "github.com/openshift/client-go/apps/clientset/versioned"
+	appsclienttyped "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+
+	"github.com/openshift/library-go/pkg/apps/appsserialization"
+	"github.com/openshift/library-go/pkg/apps/appsutil"
+	appsapi "github.com/openshift/origin/pkg/apps/apis/apps"
+)
+
+// HistoryREST provides the rollout history of a DeploymentConfig, i.e. the ordered set of
+// revisions that are eligible to be used as the target of a rollback.
+type HistoryREST struct {
+	dn appsclienttyped.DeploymentConfigsGetter
+	rn corev1client.ReplicationControllersGetter
+}
+
+var _ rest.Getter = &HistoryREST{}
+
+// NewHistoryREST safely creates a new HistoryREST.
+func NewHistoryREST(appsclient appsclient.Interface, kc kubernetes.Interface) *HistoryREST {
+	return &HistoryREST{
+		dn: appsclient.AppsV1(),
+		rn: kc.CoreV1(),
+	}
+}
+
+// New creates an empty DeploymentConfigRollbackHistoryList resource.
+func (r *HistoryREST) New() runtime.Object {
+	return &appsapi.DeploymentConfigRollbackHistoryList{}
+}
+
+// Get returns the ordered list of revisions available for rollback of the named DeploymentConfig,
+// most recent first. This centralizes the enumerate-RCs-and-decode-each-one logic that rollback
+// clients would otherwise have to duplicate.
+func (r *HistoryREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	namespace, ok := apirequest.NamespaceFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("namespace parameter required.")
+	}
+
+	config, err := r.dn.DeploymentConfigs(namespace).Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, apierrors.NewNotFound(apps.Resource("deploymentconfigs"), name)
+	}
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("cannot get deployment config %q: %v", name, err))
+	}
+
+	selector := appsutil.ConfigSelector(config.Name)
+	rcList, err := r.rn.ReplicationControllers(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("cannot list deployments for %q: %v", name, err))
+	}
+
+	history := make([]appsapi.DeploymentConfigRollbackHistory, 0, len(rcList.Items))
+	for i := range rcList.Items {
+		rc := &rcList.Items[i]
+		revision := appsutil.DeploymentVersionFor(rc)
+		if revision < 0 {
+			continue
+		}
+
+		decoded, err := appsserialization.DecodeDeploymentConfig(rc)
+		changeCause := rc.Annotations["kubernetes.io/change-cause"]
+		if err == nil && changeCause == "" {
+			changeCause = decoded.Annotations["kubernetes.io/change-cause"]
+		}
+
+		replicas := int32(0)
+		if rc.Spec.Replicas != nil {
+			replicas = *rc.Spec.Replicas
+		}
+
+		history = append(history, appsapi.DeploymentConfigRollbackHistory{
+			Revision:                  revision,
+			ReplicationControllerName: rc.Name,
+			CreationTimestamp:         rc.CreationTimestamp,
+			ChangeCause:               changeCause,
+			Replicas:                  replicas,
+		})
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Revision > history[j].Revision
+	})
+
+	return &appsapi.DeploymentConfigRollbackHistoryList{Items: history}, nil
+}
@@ -0,0 +1,53 @@
+package rollback
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	appsfake "github.com/openshift/client-go/apps/clientset/versioned/fake"
+)
+
+func TestHistoryRESTGetNotFound(t *testing.T) {
+	appsClient := appsfake.NewSimpleClientset()
+	kubeClient := kubefake.NewSimpleClientset()
+
+	r := NewHistoryREST(appsClient, kubeClient)
+	ctx := apirequest.WithNamespace(apirequest.NewContext(), "ns")
+
+	_, err := r.Get(ctx, "missing", &metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected a NotFound error, got %v", err)
+	}
+}
+
+// TestHistoryRESTGetPropagatesUnderlyingError guards against collapsing every DeploymentConfig
+// lookup failure into a blanket NotFound, which previously discarded the real error.
+func TestHistoryRESTGetPropagatesUnderlyingError(t *testing.T) {
+	appsClient := appsfake.NewSimpleClientset()
+	appsClient.PrependReactor("get", "deploymentconfigs", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("etcd is unavailable")
+	})
+	kubeClient := kubefake.NewSimpleClientset()
+
+	r := NewHistoryREST(appsClient, kubeClient)
+	ctx := apirequest.WithNamespace(apirequest.NewContext(), "ns")
+
+	_, err := r.Get(ctx, "config", &metav1.GetOptions{})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if apierrors.IsNotFound(err) {
+		t.Fatalf("expected the underlying error to propagate, got a NotFound error instead: %v", err)
+	}
+	if !strings.Contains(err.Error(), "etcd is unavailable") {
+		t.Fatalf("expected underlying error message to be preserved, got %v", err)
+	}
+}
@@ -3,18 +3,21 @@ package rollback
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/client-go/kubernetes"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
-	"k8s.io/kubernetes/pkg/api/legacyscheme"
 
 	"github.com/openshift/api/apps"
+	appsv1 "github.com/openshift/api/apps/v1"
 	appsclient "github.com/openshift/client-go/apps/clientset/versioned"
 	appsclienttyped "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
 
@@ -22,6 +25,7 @@ import (
 	"github.com/openshift/library-go/pkg/apps/appsutil"
 	appsapi "github.com/openshift/origin/pkg/apps/apis/apps"
 	"github.com/openshift/origin/pkg/apps/apis/apps/validation"
+	rollbackscheme "github.com/openshift/origin/pkg/apps/apiserver/registry/rollback/scheme"
 )
 
 // REST provides a rollback generation endpoint. Only the Create method is implemented.
@@ -29,6 +33,7 @@ type REST struct {
 	generator RollbackGenerator
 	dn        appsclienttyped.DeploymentConfigsGetter
 	rn        corev1client.ReplicationControllersGetter
+	scheme    *runtime.Scheme
 }
 
 var _ rest.Creater = &REST{}
@@ -39,6 +44,7 @@ func NewREST(appsclient appsclient.Interface, kc kubernetes.Interface) *REST {
 		generator: NewRollbackGenerator(),
 		dn:        appsclient.AppsV1(),
 		rn:        kc.CoreV1(),
+		scheme:    rollbackscheme.Scheme,
 	}
 }
 
@@ -61,14 +67,21 @@ func (r *REST) Create(ctx context.Context, obj runtime.Object, createValidation
 	if errs := validation.ValidateDeploymentConfigRollback(rollback); len(errs) > 0 {
 		return nil, apierrors.NewInvalid(apps.Kind("DeploymentConfigRollback"), rollback.Name, errs)
 	}
-	if err := createValidation(obj); err != nil {
-		return nil, err
+
+	preview := dryRun(options)
+	// A dry-run Create only previews what a rollback would do; it must not run the persistence
+	// hooks (admission, quota, etc.) a real Create would, since nothing is actually being created.
+	if !preview {
+		if err := createValidation(obj); err != nil {
+			return nil, err
+		}
 	}
 
 	from, err := r.dn.DeploymentConfigs(namespace).Get(rollback.Name, metav1.GetOptions{})
 	if err != nil {
 		return nil, newInvalidError(rollback, fmt.Sprintf("cannot get deployment config %q: %v", rollback.Name, err))
 	}
+	original := from.DeepCopy()
 
 	switch from.Status.LatestVersion {
 	case 0:
@@ -79,16 +92,9 @@ func (r *REST) Create(ctx context.Context, obj runtime.Object, createValidation
 		return nil, newInvalidError(rollback, fmt.Sprintf("version %d is already the latest", rollback.Spec.Revision))
 	}
 
-	revision := from.Status.LatestVersion - 1
-	if rollback.Spec.Revision > 0 {
-		revision = rollback.Spec.Revision
-	}
-
-	// Find the target deployment and decode its config.
-	name := appsutil.DeploymentNameForConfigVersion(from.Name, revision)
-	targetDeployment, err := r.rn.ReplicationControllers(namespace).Get(name, metav1.GetOptions{})
+	targetDeployment, err := r.resolveTarget(namespace, from, rollback)
 	if err != nil {
-		return nil, newInvalidError(rollback, err.Error())
+		return nil, err
 	}
 
 	to, err := appsserialization.DecodeDeploymentConfig(targetDeployment)
@@ -97,7 +103,7 @@ func (r *REST) Create(ctx context.Context, obj runtime.Object, createValidation
 	}
 
 	toInternal := &appsapi.DeploymentConfig{}
-	if err := legacyscheme.Scheme.Convert(to, toInternal, nil); err != nil {
+	if err := r.scheme.Convert(to, toInternal, nil); err != nil {
 		return nil, apierrors.NewInternalError(err)
 	}
 
@@ -109,11 +115,197 @@ func (r *REST) Create(ctx context.Context, obj runtime.Object, createValidation
 	}
 
 	fromInternal := &appsapi.DeploymentConfig{}
-	if err := legacyscheme.Scheme.Convert(from, fromInternal, nil); err != nil {
+	if err := r.scheme.Convert(from, fromInternal, nil); err != nil {
 		return nil, apierrors.NewInternalError(err)
 	}
 
-	return r.generator.GenerateRollback(fromInternal, toInternal, &rollback.Spec)
+	generated, err := r.generator.GenerateRollback(fromInternal, toInternal, &rollback.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if !preview {
+		return generated, nil
+	}
+
+	result, err := r.newRollbackPreview(original, generated)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return result, nil
+}
+
+// dryRun returns true if the caller asked for the rollback to be previewed rather than applied.
+func dryRun(options *metav1.CreateOptions) bool {
+	if options == nil {
+		return false
+	}
+	for _, mode := range options.DryRun {
+		if mode == metav1.DryRunAll {
+			return true
+		}
+	}
+	return false
+}
+
+// newRollbackPreview builds a DeploymentConfigRollbackPreview describing the difference between
+// the current DeploymentConfig and the one the rollback would generate, without requiring the
+// caller to do its own get-then-diff against the generated result.
+func (r *REST) newRollbackPreview(from *appsv1.DeploymentConfig, generated *appsapi.DeploymentConfig) (*appsapi.DeploymentConfigRollbackPreview, error) {
+	generatedExternal := &appsv1.DeploymentConfig{}
+	if err := r.scheme.Convert(generated, generatedExternal, nil); err != nil {
+		return nil, err
+	}
+
+	fromJSON, err := runtime.Encode(rollbackscheme.Codecs.LegacyCodec(appsv1.SchemeGroupVersion), from)
+	if err != nil {
+		return nil, err
+	}
+	toJSON, err := runtime.Encode(rollbackscheme.Codecs.LegacyCodec(appsv1.SchemeGroupVersion), generatedExternal)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := strategicpatch.CreateTwoWayMergePatch(fromJSON, toJSON, &appsv1.DeploymentConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &appsapi.DeploymentConfigRollbackPreview{
+		GeneratedDeploymentConfig: generated,
+		Diff:                      string(patch),
+		ChangedImages:             diffImages(from, generatedExternal),
+		ChangedEnvironment:        diffEnvironment(from, generatedExternal),
+	}
+	if fromReplicas, toReplicas := from.Spec.Replicas, generatedExternal.Spec.Replicas; fromReplicas != toReplicas {
+		preview.ReplicaChange = &appsapi.ReplicaChange{From: fromReplicas, To: toReplicas}
+	}
+	return preview, nil
+}
+
+func diffImages(from, to *appsv1.DeploymentConfig) []appsapi.ImageChange {
+	toByName := map[string]string{}
+	for _, c := range to.Spec.Template.Spec.Containers {
+		toByName[c.Name] = c.Image
+	}
+
+	var changes []appsapi.ImageChange
+	for _, c := range from.Spec.Template.Spec.Containers {
+		if toImage, ok := toByName[c.Name]; ok && toImage != c.Image {
+			changes = append(changes, appsapi.ImageChange{Container: c.Name, From: c.Image, To: toImage})
+		}
+	}
+	return changes
+}
+
+func diffEnvironment(from, to *appsv1.DeploymentConfig) []appsapi.EnvVarChange {
+	toContainers := map[string]map[string]string{}
+	for _, c := range to.Spec.Template.Spec.Containers {
+		env := map[string]string{}
+		for _, e := range c.Env {
+			env[e.Name] = e.Value
+		}
+		toContainers[c.Name] = env
+	}
+
+	var changes []appsapi.EnvVarChange
+	for _, c := range from.Spec.Template.Spec.Containers {
+		toEnv, ok := toContainers[c.Name]
+		if !ok {
+			continue
+		}
+		fromEnv := map[string]string{}
+		for _, e := range c.Env {
+			fromEnv[e.Name] = e.Value
+		}
+		for name, fromValue := range fromEnv {
+			if toValue, ok := toEnv[name]; !ok || toValue != fromValue {
+				changes = append(changes, appsapi.EnvVarChange{Container: c.Name, Name: name, From: fromValue, To: toEnv[name]})
+			}
+		}
+		for name, toValue := range toEnv {
+			if _, ok := fromEnv[name]; !ok {
+				changes = append(changes, appsapi.EnvVarChange{Container: c.Name, Name: name, From: "", To: toValue})
+			}
+		}
+	}
+	return changes
+}
+
+// resolveTarget finds the ReplicationController to roll back to, honoring whichever target
+// selector the caller set on rollback.Spec: an exact RC name, a change-cause substring, a label
+// selector over the config's historical RCs, or (the default) a numeric revision.
+func (r *REST) resolveTarget(namespace string, from *appsv1.DeploymentConfig, rollback *appsapi.DeploymentConfigRollback) (*corev1.ReplicationController, error) {
+	spec := rollback.Spec
+
+	if len(spec.TargetReplicationController) > 0 {
+		rc, err := r.rn.ReplicationControllers(namespace).Get(spec.TargetReplicationController, metav1.GetOptions{})
+		if err != nil {
+			return nil, newInvalidError(rollback, err.Error())
+		}
+		if appsutil.DeploymentConfigNameFor(rc) != from.Name {
+			return nil, newInvalidError(rollback, fmt.Sprintf("%q is not a deployment of %q", spec.TargetReplicationController, from.Name))
+		}
+		return rc, nil
+	}
+
+	if len(spec.ChangeCauseContains) > 0 || spec.TargetSelector != nil {
+		return r.resolveTargetBySelector(namespace, from, rollback)
+	}
+
+	revision := from.Status.LatestVersion - 1
+	if spec.Revision > 0 {
+		revision = spec.Revision
+	}
+
+	name := appsutil.DeploymentNameForConfigVersion(from.Name, revision)
+	rc, err := r.rn.ReplicationControllers(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, newInvalidError(rollback, err.Error())
+	}
+	return rc, nil
+}
+
+// resolveTargetBySelector lists the historical ReplicationControllers owned by the config,
+// narrows them by the rollback's label selector and/or change-cause substring, and returns the
+// newest match.
+func (r *REST) resolveTargetBySelector(namespace string, from *appsv1.DeploymentConfig, rollback *appsapi.DeploymentConfigRollback) (*corev1.ReplicationController, error) {
+	spec := rollback.Spec
+
+	selector := appsutil.ConfigSelector(from.Name)
+	if spec.TargetSelector != nil {
+		extra, err := metav1.LabelSelectorAsSelector(spec.TargetSelector)
+		if err != nil {
+			return nil, newInvalidError(rollback, fmt.Sprintf("invalid targetSelector: %v", err))
+		}
+		if requirements, selectable := extra.Requirements(); selectable {
+			selector = selector.Add(requirements...)
+		}
+	}
+
+	rcList, err := r.rn.ReplicationControllers(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, newInvalidError(rollback, err.Error())
+	}
+
+	var best *corev1.ReplicationController
+	var bestRevision int64
+	for i := range rcList.Items {
+		rc := &rcList.Items[i]
+		if len(spec.ChangeCauseContains) > 0 && !strings.Contains(rc.Annotations["kubernetes.io/change-cause"], spec.ChangeCauseContains) {
+			continue
+		}
+		revision := appsutil.DeploymentVersionFor(rc)
+		if revision < 0 {
+			continue
+		}
+		if best == nil || revision > bestRevision {
+			best, bestRevision = rc, revision
+		}
+	}
+	if best == nil {
+		return nil, newInvalidError(rollback, "no matching deployment found for the given target selector")
+	}
+	return best, nil
 }
 
 func newInvalidError(rollback *appsapi.DeploymentConfigRollback, reason string) error {
@@ -0,0 +1,83 @@
+package rollback
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsapi "github.com/openshift/origin/pkg/apps/apis/apps"
+)
+
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *metav1.CreateOptions
+		want    bool
+	}{
+		{name: "nil options", options: nil, want: false},
+		{name: "no dry run", options: &metav1.CreateOptions{}, want: false},
+		{name: "dry run all", options: &metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}, want: true},
+		{name: "unrelated dry run mode", options: &metav1.CreateOptions{DryRun: []string{"SomethingElse"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dryRun(tt.options); got != tt.want {
+				t.Errorf("dryRun(%+v) = %v, want %v", tt.options, got, tt.want)
+			}
+		})
+	}
+}
+
+func container(name, image string, env ...corev1.EnvVar) corev1.Container {
+	return corev1.Container{Name: name, Image: image, Env: env}
+}
+
+func deploymentConfigWithContainers(containers ...corev1.Container) *appsv1.DeploymentConfig {
+	dc := &appsv1.DeploymentConfig{}
+	dc.Spec.Template = &corev1.PodTemplateSpec{}
+	dc.Spec.Template.Spec.Containers = containers
+	return dc
+}
+
+func TestDiffImages(t *testing.T) {
+	from := deploymentConfigWithContainers(container("web", "example.com/web:v1"), container("sidecar", "example.com/sidecar:v1"))
+	to := deploymentConfigWithContainers(container("web", "example.com/web:v2"), container("sidecar", "example.com/sidecar:v1"))
+
+	changes := diffImages(from, to)
+	if len(changes) != 1 {
+		t.Fatalf("expected exactly one image change, got %v", changes)
+	}
+	if changes[0].Container != "web" || changes[0].From != "example.com/web:v1" || changes[0].To != "example.com/web:v2" {
+		t.Errorf("unexpected image change: %+v", changes[0])
+	}
+}
+
+func TestDiffEnvironment(t *testing.T) {
+	from := deploymentConfigWithContainers(container("web", "example.com/web:v1",
+		corev1.EnvVar{Name: "LOG_LEVEL", Value: "info"},
+		corev1.EnvVar{Name: "REMOVED", Value: "x"},
+	))
+	to := deploymentConfigWithContainers(container("web", "example.com/web:v1",
+		corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"},
+		corev1.EnvVar{Name: "ADDED", Value: "y"},
+	))
+
+	changes := diffEnvironment(from, to)
+	byName := map[string]appsapi.EnvVarChange{}
+	for _, c := range changes {
+		byName[c.Name] = c
+	}
+
+	if got := byName["LOG_LEVEL"]; got.From != "info" || got.To != "debug" {
+		t.Errorf("expected LOG_LEVEL to change from info to debug, got %+v", got)
+	}
+	if got := byName["REMOVED"]; got.From != "x" || got.To != "" {
+		t.Errorf("expected REMOVED to be dropped, got %+v", got)
+	}
+	if got := byName["ADDED"]; got.From != "" || got.To != "y" {
+		t.Errorf("expected ADDED to be added, got %+v", got)
+	}
+}
@@ -0,0 +1,43 @@
+// Package scheme provides a conversion-only runtime.Scheme for the rollback REST storage. It
+// knows just enough about the internal and external DeploymentConfig types to convert between
+// them, so the rollback package can avoid importing k8s.io/kubernetes/pkg/api/legacyscheme (and
+// the rest of the monolithic API server that comes with it) purely to run two Convert calls.
+package scheme
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	coreinstall "k8s.io/kubernetes/pkg/apis/core/install"
+
+	appsapi "github.com/openshift/origin/pkg/apps/apis/apps"
+	appsapiv1 "github.com/openshift/origin/pkg/apps/apis/apps/v1"
+)
+
+var (
+	// Scheme knows the appsv1 external types and the appsapi internal types used by the rollback
+	// generator, along with the generated conversion functions between them.
+	Scheme = runtime.NewScheme()
+
+	// Codecs provides encode/decode for the external appsv1 types registered on Scheme, used to
+	// produce the strategic-merge-patch for a rollback preview.
+	Codecs = serializer.NewCodecFactory(Scheme)
+)
+
+func init() {
+	// DeploymentConfig nests corev1.PodTemplateSpec, so the core internal<->external
+	// conversions have to be registered too, exactly as legacyscheme.Scheme does.
+	coreinstall.Install(Scheme)
+
+	utilruntime.Must(appsapi.AddToScheme(Scheme))
+	// appsapiv1 (pkg/apps/apis/apps/v1) is origin's own conversion package: unlike the bare
+	// external-types-only github.com/openshift/api/apps/v1, its Install carries the generated
+	// DeploymentConfig internal<->external conversion functions this scheme needs.
+	utilruntime.Must(appsapiv1.Install(Scheme))
+}
+
+// Convert converts in to out using Scheme's registered converter. It is a thin wrapper so callers
+// don't need to reach for the package-level Scheme directly.
+func Convert(in, out interface{}) error {
+	return Scheme.Convert(in, out, nil)
+}
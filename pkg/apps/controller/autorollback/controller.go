@@ -0,0 +1,286 @@
+package autorollback
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsclient "github.com/openshift/client-go/apps/clientset/versioned"
+	"github.com/openshift/library-go/pkg/apps/appsutil"
+	appsapi "github.com/openshift/origin/pkg/apps/apis/apps"
+	"github.com/openshift/origin/pkg/apps/apiserver/registry/rollback"
+	rollbackscheme "github.com/openshift/origin/pkg/apps/apiserver/registry/rollback/scheme"
+)
+
+const (
+	// AutoRollbackAnnotation opts a DeploymentConfig into automatic rollback on deployment failure.
+	AutoRollbackAnnotation = "apps.openshift.io/auto-rollback"
+	// AutoRollbackWindowAnnotation bounds how long after a deployment starts it is still eligible
+	// to trigger an automatic rollback when it fails. Defaults to 10 minutes.
+	AutoRollbackWindowAnnotation = "apps.openshift.io/auto-rollback-window"
+	// AutoRollbackMaxAttemptsAnnotation caps how many times a config may be auto-rolled-back.
+	// Defaults to 1.
+	AutoRollbackMaxAttemptsAnnotation = "apps.openshift.io/auto-rollback-max-attempts"
+	// AutoRollbackAttemptsAnnotation records how many automatic rollbacks have fired for a config,
+	// so the controller never loops past the configured maximum.
+	AutoRollbackAttemptsAnnotation = "apps.openshift.io/auto-rollback-attempts"
+
+	defaultAutoRollbackWindow      = 10 * time.Minute
+	defaultAutoRollbackMaxAttempts = 1
+	changeCauseAnnotation          = "kubernetes.io/change-cause"
+)
+
+// Controller watches ReplicationControllers owned by a DeploymentConfig and, when the latest
+// deployment fails within its grace window, automatically rolls the config back to its previous
+// successful revision by going through the same REST.Create path a manual rollback would use.
+type Controller struct {
+	appsClient appsclient.Interface
+	kubeClient kubernetes.Interface
+	rollback   *rollback.REST
+
+	rcLister       corev1listers.ReplicationControllerLister
+	rcListerSynced cache.InformerSynced
+
+	queue         workqueue.RateLimitingInterface
+	eventRecorder record.EventRecorder
+}
+
+// NewController creates a new auto-rollback Controller.
+func NewController(rcInformer corev1informers.ReplicationControllerInformer, appsClient appsclient.Interface, kubeClient kubernetes.Interface, eventRecorder record.EventRecorder) *Controller {
+	c := &Controller{
+		appsClient:    appsClient,
+		kubeClient:    kubeClient,
+		rollback:      rollback.NewREST(appsClient, kubeClient),
+		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "auto-rollback"),
+		eventRecorder: eventRecorder,
+	}
+
+	rcInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueue,
+		UpdateFunc: func(_, cur interface{}) { c.enqueue(cur) },
+	})
+	c.rcLister = rcInformer.Lister()
+	c.rcListerSynced = rcInformer.Informer().HasSynced
+
+	return c
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run starts the controller's workers and blocks until stopCh is closed.
+func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting auto-rollback controller")
+	defer klog.Infof("Shutting down auto-rollback controller")
+
+	if !cache.WaitForCacheSync(stopCh, c.rcListerSynced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+func (c *Controller) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(key.(string)); err != nil {
+		runtime.HandleError(fmt.Errorf("error syncing %q: %v", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// sync inspects the ReplicationController named by key and, if it is the latest deployment of an
+// opted-in DeploymentConfig and has failed within the grace window, triggers an automatic
+// rollback.
+func (c *Controller) sync(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	rc, err := c.rcLister.ReplicationControllers(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if appsutil.DeploymentStatusFor(rc) != appsv1.DeploymentStatusFailed {
+		return nil
+	}
+
+	configName := appsutil.DeploymentConfigNameFor(rc)
+	if len(configName) == 0 {
+		return nil
+	}
+
+	config, err := c.appsClient.AppsV1().DeploymentConfigs(namespace).Get(configName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.maybeRollback(config, rc)
+}
+
+func (c *Controller) maybeRollback(config *appsv1.DeploymentConfig, rc *corev1.ReplicationController) error {
+	if config.Annotations[AutoRollbackAnnotation] != "true" {
+		return nil
+	}
+
+	// Same guard rails REST.Create already applies: nothing to roll back to before version 2.
+	if config.Status.LatestVersion <= 1 {
+		return nil
+	}
+
+	deployedRevision := appsutil.DeploymentVersionFor(rc)
+	if deployedRevision < 0 || deployedRevision != config.Status.LatestVersion {
+		// Only the RC backing the current latest deployment can trigger an auto-rollback.
+		return nil
+	}
+
+	window := defaultAutoRollbackWindow
+	if raw, ok := config.Annotations[AutoRollbackWindowAnnotation]; ok {
+		if parsed, err := time.ParseDuration(raw); err == nil {
+			window = parsed
+		}
+	}
+	if time.Since(rc.CreationTimestamp.Time) > window {
+		return nil
+	}
+
+	maxAttempts := defaultAutoRollbackMaxAttempts
+	if raw, ok := config.Annotations[AutoRollbackMaxAttemptsAnnotation]; ok {
+		if parsed, err := strconv.Atoi(raw); err == nil {
+			maxAttempts = parsed
+		}
+	}
+	attempts, _ := strconv.Atoi(config.Annotations[AutoRollbackAttemptsAnnotation])
+	if attempts >= maxAttempts {
+		return nil
+	}
+
+	targetRevision, err := c.lastCompleteRevisionBefore(config, deployedRevision)
+	if err != nil {
+		return err
+	}
+	if targetRevision < 0 {
+		// Nothing to roll back to: every earlier revision also failed.
+		return nil
+	}
+
+	reason := appsutil.DeploymentStatusReasonFor(rc)
+	changeCause := fmt.Sprintf("auto-rollback: deployment-%d failed: %s", deployedRevision, reason)
+
+	rollbackReq := &appsapi.DeploymentConfigRollback{
+		Name: config.Name,
+		UpdatedAnnotations: map[string]string{
+			changeCauseAnnotation:          changeCause,
+			AutoRollbackAttemptsAnnotation: strconv.Itoa(attempts + 1),
+		},
+		Spec: appsapi.DeploymentConfigRollbackSpec{
+			Revision:        targetRevision,
+			IncludeTemplate: true,
+		},
+	}
+
+	ctx := apirequest.WithNamespace(apirequest.NewContext(), config.Namespace)
+	result, err := c.rollback.Create(ctx, rollbackReq, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		c.eventRecorder.Eventf(config, corev1.EventTypeWarning, "AutoRollbackFailed", "automatic rollback of %q failed: %v", config.Name, err)
+		return err
+	}
+
+	generated, ok := result.(*appsapi.DeploymentConfig)
+	if !ok {
+		return fmt.Errorf("rollback of %q returned unexpected type %T", config.Name, result)
+	}
+
+	generatedExternal := &appsv1.DeploymentConfig{}
+	if err := rollbackscheme.Convert(generated, generatedExternal); err != nil {
+		c.eventRecorder.Eventf(config, corev1.EventTypeWarning, "AutoRollbackFailed", "automatic rollback of %q failed: %v", config.Name, err)
+		return err
+	}
+
+	// Persist the generated config. Create only ever generates the rollback object; applying it
+	// is the controller's job, the same way a human operator would apply the output of a manual
+	// rollback Create.
+	if _, err := c.appsClient.AppsV1().DeploymentConfigs(config.Namespace).Update(generatedExternal); err != nil {
+		c.eventRecorder.Eventf(config, corev1.EventTypeWarning, "AutoRollbackFailed", "automatic rollback of %q failed: %v", config.Name, err)
+		return err
+	}
+
+	c.eventRecorder.Eventf(config, corev1.EventTypeNormal, "AutoRollback", "rolled back %q to revision %d: %s", config.Name, targetRevision, reason)
+	return nil
+}
+
+// lastCompleteRevisionBefore walks the config's deployment history backwards from before
+// beforeRevision and returns the newest revision whose ReplicationController completed
+// successfully. It returns -1 if no earlier revision ever completed, so the caller knows there is
+// nothing safe to roll back to (e.g. a string of repeated failures).
+func (c *Controller) lastCompleteRevisionBefore(config *appsv1.DeploymentConfig, beforeRevision int64) (int64, error) {
+	selector := appsutil.ConfigSelector(config.Name)
+	rcList, err := c.kubeClient.CoreV1().ReplicationControllers(config.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return -1, err
+	}
+
+	best := int64(-1)
+	for i := range rcList.Items {
+		candidate := &rcList.Items[i]
+		revision := appsutil.DeploymentVersionFor(candidate)
+		if revision < 0 || revision >= beforeRevision {
+			continue
+		}
+		if appsutil.DeploymentStatusFor(candidate) != appsv1.DeploymentStatusComplete {
+			continue
+		}
+		if revision > best {
+			best = revision
+		}
+	}
+	return best, nil
+}
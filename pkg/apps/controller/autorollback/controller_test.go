@@ -0,0 +1,198 @@
+package autorollback
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsfake "github.com/openshift/client-go/apps/clientset/versioned/fake"
+	"github.com/openshift/origin/pkg/apps/apiserver/registry/rollback"
+)
+
+// TestMaybeRollbackPersistsGeneratedConfig guards against maybeRollback discarding the generated
+// rollback instead of applying it: it must call Update against the real DeploymentConfig and
+// record the attempts annotation there, not just on the object rollback.REST.Create returns.
+func TestMaybeRollbackPersistsGeneratedConfig(t *testing.T) {
+	config := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				AutoRollbackAnnotation: "true",
+			},
+		},
+		Status: appsv1.DeploymentConfigStatus{LatestVersion: 2},
+	}
+	previousRC := &corev1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-1",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				"openshift.io/deployment-config.name":           "web",
+				"openshift.io/deployment-config.latest-version": "1",
+				"openshift.io/deployment.phase":                 string(appsv1.DeploymentStatusComplete),
+			},
+		},
+	}
+	failedRC := &corev1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "web-2",
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(time.Now()),
+			Annotations: map[string]string{
+				"openshift.io/deployment-config.name":           "web",
+				"openshift.io/deployment-config.latest-version": "2",
+			},
+		},
+	}
+
+	appsClient := appsfake.NewSimpleClientset(config)
+	kubeClient := kubefake.NewSimpleClientset(previousRC, failedRC)
+
+	c := &Controller{
+		appsClient:    appsClient,
+		kubeClient:    kubeClient,
+		rollback:      rollback.NewREST(appsClient, kubeClient),
+		eventRecorder: record.NewFakeRecorder(10),
+	}
+
+	if err := c.maybeRollback(config, failedRC); err != nil {
+		t.Fatalf("maybeRollback returned error: %v", err)
+	}
+
+	updated, err := appsClient.AppsV1().DeploymentConfigs("ns").Get("web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get updated config: %v", err)
+	}
+	if updated.Annotations[AutoRollbackAttemptsAnnotation] != "1" {
+		t.Errorf("expected the attempts annotation to be recorded on the persisted config, got %q", updated.Annotations[AutoRollbackAttemptsAnnotation])
+	}
+}
+
+// TestMaybeRollbackSkipsFailedIntermediateRevisions ensures the controller walks past a revision
+// that also failed instead of blindly rolling back to LatestVersion-1.
+func TestMaybeRollbackSkipsFailedIntermediateRevisions(t *testing.T) {
+	config := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				AutoRollbackAnnotation: "true",
+			},
+		},
+		Status: appsv1.DeploymentConfigStatus{LatestVersion: 3},
+	}
+	completeRC := &corev1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-1",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				"openshift.io/deployment-config.name":           "web",
+				"openshift.io/deployment-config.latest-version": "1",
+				"openshift.io/deployment.phase":                 string(appsv1.DeploymentStatusComplete),
+			},
+		},
+	}
+	previouslyFailedRC := &corev1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-2",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				"openshift.io/deployment-config.name":           "web",
+				"openshift.io/deployment-config.latest-version": "2",
+				"openshift.io/deployment.phase":                 string(appsv1.DeploymentStatusFailed),
+			},
+		},
+	}
+	failedRC := &corev1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "web-3",
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(time.Now()),
+			Annotations: map[string]string{
+				"openshift.io/deployment-config.name":           "web",
+				"openshift.io/deployment-config.latest-version": "3",
+			},
+		},
+	}
+
+	appsClient := appsfake.NewSimpleClientset(config)
+	kubeClient := kubefake.NewSimpleClientset(completeRC, previouslyFailedRC, failedRC)
+	recorder := record.NewFakeRecorder(10)
+
+	c := &Controller{
+		appsClient:    appsClient,
+		kubeClient:    kubeClient,
+		rollback:      rollback.NewREST(appsClient, kubeClient),
+		eventRecorder: recorder,
+	}
+
+	if err := c.maybeRollback(config, failedRC); err != nil {
+		t.Fatalf("maybeRollback returned error: %v", err)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "to revision 1") {
+			t.Errorf("expected rollback to skip the also-failed revision 2 and land on revision 1, got event %q", event)
+		}
+	default:
+		t.Fatal("expected an AutoRollback event to be recorded")
+	}
+}
+
+// TestMaybeRollbackSkipsWhenAttemptsExhausted ensures the controller doesn't keep retrying once
+// it has already used up its configured attempt budget.
+func TestMaybeRollbackSkipsWhenAttemptsExhausted(t *testing.T) {
+	config := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				AutoRollbackAnnotation:            "true",
+				AutoRollbackAttemptsAnnotation:    "1",
+				AutoRollbackMaxAttemptsAnnotation: "1",
+			},
+		},
+		Status: appsv1.DeploymentConfigStatus{LatestVersion: 2},
+	}
+	failedRC := &corev1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "web-2",
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(time.Now()),
+			Annotations: map[string]string{
+				"openshift.io/deployment-config.name":           "web",
+				"openshift.io/deployment-config.latest-version": "2",
+			},
+		},
+	}
+
+	appsClient := appsfake.NewSimpleClientset(config)
+	kubeClient := kubefake.NewSimpleClientset(failedRC)
+
+	c := &Controller{
+		appsClient:    appsClient,
+		kubeClient:    kubeClient,
+		rollback:      rollback.NewREST(appsClient, kubeClient),
+		eventRecorder: record.NewFakeRecorder(10),
+	}
+
+	if err := c.maybeRollback(config, failedRC); err != nil {
+		t.Fatalf("maybeRollback returned error: %v", err)
+	}
+
+	updated, err := appsClient.AppsV1().DeploymentConfigs("ns").Get("web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get updated config: %v", err)
+	}
+	if updated.Annotations[AutoRollbackAttemptsAnnotation] != "1" {
+		t.Errorf("expected no further rollback attempt, attempts annotation changed to %q", updated.Annotations[AutoRollbackAttemptsAnnotation])
+	}
+}